@@ -0,0 +1,145 @@
+package tickstore
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Candle is one OHLCV bar for a single instrument over a fixed interval.
+type Candle struct {
+	Token     uint32
+	Interval  string
+	OpenTime  time.Time
+	CloseTime time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+	NumTicks  uint32
+}
+
+// Aggregator rolls raw ticks up into Candle bars for one or more configured
+// intervals (e.g. time.Minute, 5*time.Minute, 24*time.Hour), bucketed by
+// each tick's exchange timestamp rather than the time it was received.
+type Aggregator struct {
+	intervals []time.Duration
+	out       chan Candle
+
+	mu      sync.Mutex
+	current map[time.Duration]map[uint32]*Candle
+
+	closeOnce   sync.Once
+	droppedBars uint64
+}
+
+// NewAggregator returns an Aggregator that emits bars for each of the given
+// intervals on its Candles channel as they close.
+func NewAggregator(intervals ...time.Duration) *Aggregator {
+	current := make(map[time.Duration]map[uint32]*Candle, len(intervals))
+	for _, iv := range intervals {
+		current[iv] = make(map[uint32]*Candle)
+	}
+	return &Aggregator{
+		intervals: intervals,
+		out:       make(chan Candle, 256),
+		current:   current,
+	}
+}
+
+// Candles returns the channel closed bars are emitted on.
+func (a *Aggregator) Candles() <-chan Candle {
+	return a.out
+}
+
+// DroppedBars reports how many closed bars were dropped because Candles()
+// wasn't being drained fast enough.
+func (a *Aggregator) DroppedBars() uint64 {
+	return atomic.LoadUint64(&a.droppedBars)
+}
+
+// emit sends a closed bar to Candles() without blocking; if the channel is
+// full, the bar is dropped and counted rather than stalling the caller
+// (Add, which runs synchronously on the tick hot path).
+func (a *Aggregator) emit(c Candle) {
+	select {
+	case a.out <- c:
+	default:
+		atomic.AddUint64(&a.droppedBars, 1)
+	}
+}
+
+// Add rolls tick into the open bar for every configured interval, emitting
+// and resetting any bar whose window the tick has moved past.
+func (a *Aggregator) Add(tick tickData) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, iv := range a.intervals {
+		bucket := tick.TimeStamp.Truncate(iv)
+		candles := a.current[iv]
+		c := candles[tick.Token]
+
+		if c != nil && !c.OpenTime.Equal(bucket) {
+			a.emit(*c)
+			c = nil
+		}
+
+		if c == nil {
+			c = &Candle{
+				Token:     tick.Token,
+				Interval:  intervalLabel(iv),
+				OpenTime:  bucket,
+				CloseTime: bucket.Add(iv),
+				Open:      tick.LastPrice,
+				High:      tick.LastPrice,
+				Low:       tick.LastPrice,
+			}
+			candles[tick.Token] = c
+		}
+
+		c.Close = tick.LastPrice
+		if tick.LastPrice > c.High {
+			c.High = tick.LastPrice
+		}
+		if tick.LastPrice < c.Low {
+			c.Low = tick.LastPrice
+		}
+		c.Volume += tick.Volume
+		c.NumTicks++
+	}
+}
+
+// Close flushes any still-open bars to the Candles channel and closes it.
+// It should only be called once the aggregator will receive no more ticks,
+// and is safe to call more than once.
+func (a *Aggregator) Close() {
+	a.closeOnce.Do(func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		for _, candles := range a.current {
+			for _, c := range candles {
+				a.emit(*c)
+			}
+		}
+		close(a.out)
+	})
+}
+
+// intervalLabel renders a time.Duration as the conventional bar label (1s,
+// 1m, 5m, 1h, 1d) used for Candle.Interval and the `candles` table.
+func intervalLabel(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
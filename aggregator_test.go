@@ -0,0 +1,70 @@
+package tickstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregatorRollsOverOnIntervalBoundary(t *testing.T) {
+	a := NewAggregator(time.Minute)
+
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	a.Add(tickData{Token: 1, TimeStamp: base, LastPrice: 100})
+	a.Add(tickData{Token: 1, TimeStamp: base.Add(10 * time.Second), LastPrice: 105})
+	a.Add(tickData{Token: 1, TimeStamp: base.Add(20 * time.Second), LastPrice: 95})
+
+	select {
+	case <-a.Candles():
+		t.Fatal("no bar should have closed yet; all ticks are in the same minute bucket")
+	default:
+	}
+
+	// Cross into the next minute: the first bucket's bar should close.
+	a.Add(tickData{Token: 1, TimeStamp: base.Add(70 * time.Second), LastPrice: 102})
+
+	select {
+	case c := <-a.Candles():
+		if c.Open != 100 || c.High != 105 || c.Low != 95 || c.Close != 95 || c.NumTicks != 3 {
+			t.Fatalf("unexpected closed bar: %+v", c)
+		}
+		if !c.OpenTime.Equal(base) {
+			t.Fatalf("expected bar OpenTime %v, got %v", base, c.OpenTime)
+		}
+	default:
+		t.Fatal("expected a closed bar once the tick rolled into the next bucket")
+	}
+}
+
+func TestAggregatorCloseFlushesOpenBars(t *testing.T) {
+	a := NewAggregator(time.Minute)
+	a.Add(tickData{Token: 1, TimeStamp: time.Unix(0, 0), LastPrice: 50})
+
+	a.Close()
+
+	c, ok := <-a.Candles()
+	if !ok {
+		t.Fatal("expected the still-open bar to be flushed before the channel closed")
+	}
+	if c.Open != 50 || c.Close != 50 {
+		t.Fatalf("unexpected flushed bar: %+v", c)
+	}
+
+	if _, ok := <-a.Candles(); ok {
+		t.Fatal("Candles() should be closed after Close()")
+	}
+
+	// Close must be safe to call again.
+	a.Close()
+}
+
+func TestAggregatorEmitDropsWhenFull(t *testing.T) {
+	a := NewAggregator(time.Minute)
+	// Fill the channel without draining it.
+	for i := 0; i < cap(a.out)+1; i++ {
+		a.emit(Candle{Token: uint32(i)})
+	}
+
+	if a.DroppedBars() == 0 {
+		t.Fatal("expected at least one bar to be dropped once the channel filled up")
+	}
+}
@@ -0,0 +1,97 @@
+package tickstore
+
+import (
+	"context"
+	"database/sql"
+)
+
+// CandleWriter drains Candle bars from an Aggregator and bulk-inserts them
+// into the `candles` table, mirroring ClickHouseSink's insert path.
+type CandleWriter struct {
+	db       *sql.DB
+	dumpSize int
+}
+
+// NewCandleWriter returns a CandleWriter that inserts into db in batches of
+// up to dumpSize bars.
+func NewCandleWriter(db *sql.DB, dumpSize int) *CandleWriter {
+	return &CandleWriter{db: db, dumpSize: dumpSize}
+}
+
+// Run drains candles from ch, inserting in batches of dumpSize, until ch is
+// closed. Once ctx is cancelled it stops selecting on it and switches to
+// draining ch to completion instead of returning outright: StartTicker
+// only closes the Aggregator - and so ch - once Serve has returned and the
+// pipeline has drained, which on shutdown is after its own ctx is done, so
+// if Run is given that same ctx, returning on
+// ctx.Done() would exit before the Aggregator ever emits its final,
+// still-open bars, stranding them. Once ch reports closed, it flushes
+// whatever bars are left with a fresh context - the ctx that's shutting
+// things down has usually already expired by the time the drain runs -
+// and returns.
+func (w *CandleWriter) Run(ctx context.Context, ch <-chan Candle) error {
+	batch := make([]Candle, 0, w.dumpSize)
+
+	drain := func() error {
+		for c := range ch {
+			batch = append(batch, c)
+			if len(batch) >= w.dumpSize {
+				if err := w.insert(context.Background(), batch); err != nil {
+					return err
+				}
+				batch = batch[:0]
+			}
+		}
+		return w.insert(context.Background(), batch)
+	}
+
+	for {
+		select {
+		case c, ok := <-ch:
+			if !ok {
+				return w.insert(context.Background(), batch)
+			}
+			batch = append(batch, c)
+			if len(batch) >= w.dumpSize {
+				if err := w.insert(ctx, batch); err != nil {
+					return err
+				}
+				batch = batch[:0]
+			}
+
+		case <-ctx.Done():
+			return drain()
+		}
+	}
+}
+
+// insert bulk-inserts batch into the `candles` table inside a single
+// transaction.
+func (w *CandleWriter) insert(ctx context.Context, batch []Candle) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	sqlstmt := "INSERT INTO candles (instrument_token, interval, open_time, close_time, open, high, low, close, volume, num_ticks) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+	stmt, err := tx.PrepareContext(ctx, sqlstmt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range batch {
+		if _, err := stmt.ExecContext(ctx, c.Token, c.Interval, c.OpenTime, c.CloseTime, c.Open, c.High, c.Low, c.Close, c.Volume, c.NumTicks); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
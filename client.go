@@ -0,0 +1,128 @@
+package tickstore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	kiteticker "github.com/zerodha/gokiteconnect/v4/ticker"
+)
+
+// Default tuning that NewClient sets on every Client it returns. A field the
+// caller later sets to zero is used as is, not reset to its default.
+const (
+	defaultFlushInterval  = time.Second
+	defaultMaxRetries     = 3
+	defaultRetryBaseDelay = 200 * time.Millisecond
+)
+
+// Client streams ticks from the Kite ticker websocket and hands them off to
+// a pluggable Sink for persistence. Unlike the old package-level globals,
+// all state lives on the Client, so a process can run any number of
+// Clients concurrently, each with its own connection, pipeline and sink.
+type Client struct {
+	apiKey      string
+	accessToken string
+	tokenList   []uint32
+	dumpSize    int
+
+	sink Sink
+
+	// FlushInterval bounds how long ticks can sit in an open batch before
+	// it's flushed, even if the batch hasn't reached dumpSize yet.
+	FlushInterval time.Duration
+
+	// MaxRetries and RetryBaseDelay configure the exponential-backoff retry
+	// applied to a failed Sink.Flush before the batch is handed to
+	// DeadLetter.
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+
+	// DeadLetter receives a batch's ticks if every flush retry fails. If
+	// nil and OverflowPath is set, the ticks are appended to OverflowPath
+	// as JSON lines instead; if both are unset the ticks are dropped.
+	DeadLetter func(ticks []tickData)
+
+	// OverflowPath is the fallback used by the default DeadLetter handling
+	// described above.
+	OverflowPath string
+
+	// Aggregator, if set, receives every tick alongside the sink so it can
+	// roll ticks up into OHLCV bars. Read bars off Aggregator.Candles().
+	Aggregator *Aggregator
+
+	// PingWait is the longest gap allowed between ticks before the
+	// watchdog assumes the connection has silently stalled and forces a
+	// reconnect. Zero disables the watchdog.
+	PingWait time.Duration
+
+	// Logger receives connection lifecycle events. NewClient sets it to
+	// slog.Default(); a nil Logger is not replaced with a default.
+	Logger Logger
+
+	metrics *Metrics
+
+	ticker     *kiteticker.Ticker
+	pipelineMu sync.RWMutex
+	pipeline   chan tickData
+
+	droppedTicks uint64
+	lastTick     atomic.Value // time.Time
+
+	// stopping is set the moment Stop is called, before it touches the
+	// ticker, so the watchdog can tell a deliberate shutdown apart from a
+	// silent stall and skip forcing its own close on top of it.
+	stopping int32
+
+	tokensMu   sync.RWMutex
+	subscribed []uint32
+
+	cancel   context.CancelFunc
+	stopped  chan struct{}
+	stopErr  error
+	stopOnce sync.Once
+}
+
+// NewClient returns a Client that streams ticks for tokenList using the
+// given Kite Connect credentials and hands batches of them to sink.
+// dumpSize is the capacity of the tick pipeline: ticks that arrive while it
+// is full are dropped. How many ticks go into one batch is decided by the
+// Batch that sink.MakeBatch returns. FlushInterval, MaxRetries and RetryBaseDelay can
+// be overridden on the returned Client before calling StartTicker.
+func NewClient(apiKey, accessToken string, tokenList []uint32, dumpSize int, sink Sink) *Client {
+	return &Client{
+		apiKey:         apiKey,
+		accessToken:    accessToken,
+		tokenList:      tokenList,
+		dumpSize:       dumpSize,
+		sink:           sink,
+		FlushInterval:  defaultFlushInterval,
+		MaxRetries:     defaultMaxRetries,
+		RetryBaseDelay: defaultRetryBaseDelay,
+		Logger:         defaultLogger(),
+	}
+}
+
+// DroppedTicks reports how many ticks have been dropped because the
+// pipeline was full.
+func (c *Client) DroppedTicks() uint64 {
+	return atomic.LoadUint64(&c.droppedTicks)
+}
+
+// setPipeline installs ch as the current pipeline. It's called once per
+// StartTicker, guarded so a concurrent pipelineLen (from a Prometheus
+// scrape) never reads the channel mid-(re)assignment.
+func (c *Client) setPipeline(ch chan tickData) {
+	c.pipelineMu.Lock()
+	c.pipeline = ch
+	c.pipelineMu.Unlock()
+}
+
+// pipelineLen reports how many ticks are currently buffered in the
+// pipeline, or 0 if StartTicker hasn't set one up yet.
+func (c *Client) pipelineLen() int {
+	c.pipelineMu.RLock()
+	defer c.pipelineMu.RUnlock()
+	return len(c.pipeline)
+}
@@ -0,0 +1,29 @@
+package tickstore
+
+import "sync/atomic"
+
+// Stop shuts the Client down cleanly: it cancels the context StartTicker is
+// running under, tears down the ticker connection, and then blocks until
+// StartTicker has drained the remaining ticks through one final flush,
+// closed the sink and returned. It is safe to call Stop more than once or
+// before StartTicker has been called.
+func (c *Client) Stop() error {
+	c.stopOnce.Do(func() {
+		// Mark the shutdown as deliberate before anything else, so the
+		// watchdog - which may be about to fire concurrently - knows to
+		// stand down instead of racing this close with its own.
+		atomic.StoreInt32(&c.stopping, 1)
+
+		if c.cancel != nil {
+			c.cancel()
+		}
+		if c.ticker != nil {
+			c.ticker.Close()
+		}
+	})
+
+	if c.stopped != nil {
+		<-c.stopped
+	}
+	return c.stopErr
+}
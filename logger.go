@@ -0,0 +1,18 @@
+package tickstore
+
+import "log/slog"
+
+// Logger is the structured logging interface used for ticker connection
+// lifecycle events (connect, disconnect, reconnect, errors). Any
+// *slog.Logger satisfies it; wrap another logging library with the same
+// method set to plug it in instead.
+type Logger interface {
+	Error(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Info(msg string, args ...any)
+}
+
+// defaultLogger is the Logger a Client uses when none is configured.
+func defaultLogger() Logger {
+	return slog.Default()
+}
@@ -0,0 +1,136 @@
+package tickstore
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics is a prometheus.Collector exposing everything needed to operate a
+// Client in production: tick throughput and drops, pipeline depth, flush
+// throughput and latency, DB errors, reconnects, and per-instrument
+// staleness. Register it with a prometheus.Registry to expose these
+// series.
+type Metrics struct {
+	client *Client
+
+	ticksReceived  *prometheus.CounterVec
+	ticksDropped   prometheus.Counter
+	pipelineDepth  prometheus.Gauge
+	batchesFlushed prometheus.Counter
+	flushLatency   prometheus.Histogram
+	dbErrors       prometheus.Counter
+	reconnects     prometheus.Counter
+	lastTickAge    *prometheus.GaugeVec
+
+	mu        sync.RWMutex
+	lastTicks map[uint32]time.Time
+}
+
+// NewMetrics returns a Metrics collector bound to c and attaches it as
+// c's metrics sink, so the Client starts reporting through it immediately.
+func NewMetrics(c *Client) *Metrics {
+	m := &Metrics{
+		client: c,
+		ticksReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "tickstore",
+			Name:      "ticks_received_total",
+			Help:      "Ticks received, per instrument token.",
+		}, []string{"token"}),
+		ticksDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "tickstore",
+			Name:      "ticks_dropped_total",
+			Help:      "Ticks dropped because the pipeline was full.",
+		}),
+		pipelineDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "tickstore",
+			Name:      "pipeline_depth",
+			Help:      "Current number of ticks buffered in the pipeline.",
+		}),
+		batchesFlushed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "tickstore",
+			Name:      "batches_flushed_total",
+			Help:      "Batches successfully flushed to the sink.",
+		}),
+		flushLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "tickstore",
+			Name:      "flush_latency_seconds",
+			Help:      "Latency of a single Sink.Flush call.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		dbErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "tickstore",
+			Name:      "db_errors_total",
+			Help:      "Sink.Flush calls that returned an error.",
+		}),
+		reconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "tickstore",
+			Name:      "reconnects_total",
+			Help:      "Reconnect attempts made by the ticker.",
+		}),
+		lastTickAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "tickstore",
+			Name:      "seconds_since_last_tick",
+			Help:      "Seconds since the last tick was received, per instrument token.",
+		}, []string{"token"}),
+		lastTicks: make(map[uint32]time.Time),
+	}
+	c.metrics = m
+	return m
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(m, ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.pipelineDepth.Set(float64(m.client.pipelineLen()))
+
+	m.mu.RLock()
+	for token, last := range m.lastTicks {
+		m.lastTickAge.WithLabelValues(strconv.Itoa(int(token))).Set(time.Since(last).Seconds())
+	}
+	m.mu.RUnlock()
+
+	m.ticksReceived.Collect(ch)
+	m.ticksDropped.Collect(ch)
+	m.pipelineDepth.Collect(ch)
+	m.batchesFlushed.Collect(ch)
+	m.flushLatency.Collect(ch)
+	m.dbErrors.Collect(ch)
+	m.reconnects.Collect(ch)
+	m.lastTickAge.Collect(ch)
+}
+
+// recordTick updates the per-instrument counters tracked for token.
+func (m *Metrics) recordTick(token uint32) {
+	m.ticksReceived.WithLabelValues(strconv.Itoa(int(token))).Inc()
+
+	m.mu.Lock()
+	m.lastTicks[token] = time.Now()
+	m.mu.Unlock()
+}
+
+// recordDrop counts a tick dropped because the pipeline was full.
+func (m *Metrics) recordDrop() {
+	m.ticksDropped.Inc()
+}
+
+// recordReconnect counts a reconnect attempt.
+func (m *Metrics) recordReconnect() {
+	m.reconnects.Inc()
+}
+
+// observeFlush records the latency and outcome of a single Sink.Flush call.
+func (m *Metrics) observeFlush(d time.Duration, err error) {
+	m.flushLatency.Observe(d.Seconds())
+	if err != nil {
+		m.dbErrors.Inc()
+		return
+	}
+	m.batchesFlushed.Inc()
+}
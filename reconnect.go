@@ -0,0 +1,104 @@
+package tickstore
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	kiteticker "github.com/zerodha/gokiteconnect/v4/ticker"
+)
+
+// Resubscribe replaces the live set of subscribed instrument tokens. It is
+// safe to call while the ticker is running: the new set is applied
+// immediately if connected, and is also what gets re-subscribed after every
+// future reconnect, instead of the snapshot StartTicker was called with.
+func (c *Client) Resubscribe(tokens []uint32) error {
+	c.tokensMu.Lock()
+	previous := c.subscribed
+	c.subscribed = append([]uint32(nil), tokens...)
+	current := c.subscribed
+	c.tokensMu.Unlock()
+
+	if c.ticker == nil {
+		return nil
+	}
+
+	// Unsubscribe tokens that were dropped from the set, then (re)subscribe
+	// the new set.
+	keep := make(map[uint32]bool, len(current))
+	for _, tok := range current {
+		keep[tok] = true
+	}
+	var removed []uint32
+	for _, tok := range previous {
+		if !keep[tok] {
+			removed = append(removed, tok)
+		}
+	}
+	if len(removed) > 0 {
+		if err := c.ticker.Unsubscribe(removed); err != nil {
+			return err
+		}
+	}
+	return c.subscribeTokens(current)
+}
+
+// currentTokens returns the currently subscribed token set.
+func (c *Client) currentTokens() []uint32 {
+	c.tokensMu.RLock()
+	defer c.tokensMu.RUnlock()
+	return c.subscribed
+}
+
+// subscribeTokens subscribes to tokens and sets them to full mode. It's
+// called on every connect and reconnect so a runtime Resubscribe sticks
+// across connection drops.
+func (c *Client) subscribeTokens(tokens []uint32) error {
+	if err := c.ticker.Subscribe(tokens); err != nil {
+		return err
+	}
+	return c.ticker.SetMode(kiteticker.ModeFull, tokens)
+}
+
+// watchdog force-closes the ticker connection if no tick has arrived within
+// PingWait, so a socket that's silently stalled (no error, no close, just
+// no data) gets torn down and picked up by the library's own reconnect
+// logic instead of sitting idle forever. It never forces a close once a
+// deliberate Stop is underway: Stop already closes the ticker itself, and
+// racing that with a watchdog-triggered close could hang Stop in a
+// reconnect attempt instead of letting it return.
+func (c *Client) watchdog(ctx context.Context) {
+	if c.PingWait <= 0 {
+		return
+	}
+
+	t := time.NewTicker(c.PingWait / 2)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			last, ok := c.lastTick.Load().(time.Time)
+			if !ok {
+				continue
+			}
+			if shouldForceReconnect(last, c.PingWait, atomic.LoadInt32(&c.stopping) == 1) {
+				c.ticker.Close()
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// shouldForceReconnect is the watchdog's pure stall-detection decision,
+// pulled out of watchdog so it can be tested without a real ticker
+// connection. It reports true only when ticks have actually stopped
+// arriving and no deliberate Stop is already tearing the connection down.
+func shouldForceReconnect(lastTick time.Time, pingWait time.Duration, stopping bool) bool {
+	if stopping || lastTick.IsZero() {
+		return false
+	}
+	return time.Since(lastTick) > pingWait
+}
@@ -0,0 +1,31 @@
+package tickstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShouldForceReconnect(t *testing.T) {
+	now := time.Now()
+	pingWait := 30 * time.Second
+
+	cases := []struct {
+		name     string
+		lastTick time.Time
+		stopping bool
+		want     bool
+	}{
+		{"stale tick and not stopping forces a reconnect", now.Add(-time.Minute), false, true},
+		{"recent tick does not force a reconnect", now, false, false},
+		{"zero lastTick is treated as no data yet, not a stall", time.Time{}, false, false},
+		{"a deliberate Stop suppresses the watchdog even if stale", now.Add(-time.Minute), true, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := shouldForceReconnect(tc.lastTick, pingWait, tc.stopping); got != tc.want {
+				t.Fatalf("shouldForceReconnect(%v, %v, %v) = %v, want %v", tc.lastTick, pingWait, tc.stopping, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package tickstore
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// flushWithRetry flushes batch through c.sink, retrying with exponential
+// backoff up to c.MaxRetries times. If every attempt fails, the batch is
+// handed off to deadLetter instead of killing the stream with a fatal
+// error.
+func (c *Client) flushWithRetry(ctx context.Context, batch Batch) {
+	delay := c.RetryBaseDelay
+
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		start := time.Now()
+		err := c.sink.Flush(ctx, batch)
+		if c.metrics != nil {
+			c.metrics.observeFlush(time.Since(start), err)
+		}
+		if err == nil {
+			return
+		}
+
+		if attempt == c.MaxRetries {
+			break
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			c.deadLetter(batch)
+			return
+		}
+		delay *= 2
+	}
+
+	c.deadLetter(batch)
+}
+
+// deadLetter hands off a batch that could not be flushed after retries. It
+// calls c.DeadLetter if set, otherwise appends the ticks to c.OverflowPath
+// as JSON lines, otherwise drops them.
+func (c *Client) deadLetter(batch Batch) {
+	ticks := batch.Ticks()
+	if len(ticks) == 0 {
+		return
+	}
+
+	if c.DeadLetter != nil {
+		c.DeadLetter(ticks)
+		return
+	}
+
+	if c.OverflowPath == "" {
+		return
+	}
+
+	f, err := os.OpenFile(c.OverflowPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, tick := range ticks {
+		enc.Encode(tick)
+	}
+}
@@ -0,0 +1,82 @@
+package tickstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// failingSink always fails Flush, recording how many times it was called.
+type failingSink struct {
+	calls int
+}
+
+func (s *failingSink) MakeBatch() Batch { return &sliceBatch{dumpSize: 1} }
+func (s *failingSink) Close() error     { return nil }
+
+func (s *failingSink) Flush(ctx context.Context, batch Batch) error {
+	s.calls++
+	return errors.New("boom")
+}
+
+func TestFlushWithRetryExhaustsThenDeadLetters(t *testing.T) {
+	sink := &failingSink{}
+	var deadLettered []tickData
+
+	c := NewClient("key", "token", nil, 10, sink)
+	c.MaxRetries = 2
+	c.RetryBaseDelay = time.Millisecond
+	c.DeadLetter = func(ticks []tickData) { deadLettered = append(deadLettered, ticks...) }
+
+	batch := &sliceBatch{dumpSize: 1}
+	batch.Append(tickData{Token: 42, LastPrice: 1})
+
+	c.flushWithRetry(context.Background(), batch)
+
+	if sink.calls != c.MaxRetries+1 {
+		t.Fatalf("expected %d flush attempts, got %d", c.MaxRetries+1, sink.calls)
+	}
+	if len(deadLettered) != 1 || deadLettered[0].Token != 42 {
+		t.Fatalf("expected the failed batch's ticks to be dead-lettered, got %+v", deadLettered)
+	}
+}
+
+// succeedingSink fails its first failUntil calls and succeeds on every call
+// after that.
+type succeedingSink struct {
+	failUntil int
+	calls     int
+}
+
+func (s *succeedingSink) MakeBatch() Batch { return &sliceBatch{dumpSize: 1} }
+func (s *succeedingSink) Flush(ctx context.Context, batch Batch) error {
+	s.calls++
+	if s.calls <= s.failUntil {
+		return errors.New("transient")
+	}
+	return nil
+}
+func (s *succeedingSink) Close() error { return nil }
+
+func TestFlushWithRetrySucceedsWithoutDeadLetter(t *testing.T) {
+	sink := &succeedingSink{failUntil: 1}
+	deadLettered := false
+
+	c := NewClient("key", "token", nil, 10, sink)
+	c.MaxRetries = 3
+	c.RetryBaseDelay = time.Millisecond
+	c.DeadLetter = func(ticks []tickData) { deadLettered = true }
+
+	batch := &sliceBatch{dumpSize: 1}
+	batch.Append(tickData{Token: 1, LastPrice: 1})
+
+	c.flushWithRetry(context.Background(), batch)
+
+	if sink.calls != 2 {
+		t.Fatalf("expected exactly 2 flush attempts (1 fail + 1 success), got %d", sink.calls)
+	}
+	if deadLettered {
+		t.Fatalf("DeadLetter should not be called once a retry succeeds")
+	}
+}
@@ -0,0 +1,52 @@
+package tickstore
+
+import "context"
+
+// Sink persists batches of ticks somewhere durable: ClickHouse, Kafka, a
+// Parquet file on S3, or just stdout. StartTicker flushes to whatever Sink
+// the Client was configured with, so the stream and the storage backend are
+// decoupled and the storage backend can be swapped or mocked in tests.
+type Sink interface {
+	// MakeBatch returns a fresh, empty Batch ready to accumulate ticks.
+	MakeBatch() Batch
+
+	// Flush persists batch. It runs on the pipeline consumer goroutine, so
+	// it may block, but it should respect ctx cancellation.
+	Flush(ctx context.Context, batch Batch) error
+
+	// Close releases any resources held by the sink (connections, files,
+	// producers). It is called once, when the Client is stopped.
+	Close() error
+}
+
+// Batch accumulates ticks between flushes.
+type Batch interface {
+	// Append adds a tick to the batch.
+	Append(tick tickData)
+
+	// Len reports how many ticks are currently buffered.
+	Len() int
+
+	// ShouldFlush reports whether the batch has grown large enough to be
+	// flushed on its own, independent of any time-based trigger the caller
+	// may also apply.
+	ShouldFlush() bool
+
+	// Ticks returns the ticks accumulated so far, in append order. Sinks
+	// read a batch through this method rather than asserting a concrete
+	// type, so a caller-supplied Batch implementation works with any Sink
+	// instead of silently flushing nothing.
+	Ticks() []tickData
+}
+
+// sliceBatch is a plain slice-backed Batch shared by sinks that don't need a
+// more structured accumulator than "the ticks since the last flush".
+type sliceBatch struct {
+	ticks    []tickData
+	dumpSize int
+}
+
+func (b *sliceBatch) Append(tick tickData) { b.ticks = append(b.ticks, tick) }
+func (b *sliceBatch) Len() int             { return len(b.ticks) }
+func (b *sliceBatch) ShouldFlush() bool    { return len(b.ticks) >= b.dumpSize }
+func (b *sliceBatch) Ticks() []tickData    { return b.ticks }
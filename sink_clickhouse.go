@@ -0,0 +1,60 @@
+package tickstore
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ClickHouseSink is the default Sink: it bulk-inserts ticks into the
+// `tickdata` table inside a single transaction, the same way tickstore has
+// always written to ClickHouse.
+type ClickHouseSink struct {
+	db       *sql.DB
+	dumpSize int
+}
+
+// NewClickHouseSink returns a Sink that writes to db, flushing batches of up
+// to dumpSize ticks per transaction.
+func NewClickHouseSink(db *sql.DB, dumpSize int) *ClickHouseSink {
+	return &ClickHouseSink{db: db, dumpSize: dumpSize}
+}
+
+// MakeBatch returns a fresh batch sized to dumpSize.
+func (s *ClickHouseSink) MakeBatch() Batch {
+	return &sliceBatch{dumpSize: s.dumpSize}
+}
+
+// Flush bulk-inserts batch into the `tickdata` table inside a single
+// transaction.
+func (s *ClickHouseSink) Flush(ctx context.Context, batch Batch) error {
+	ticks := batch.Ticks()
+	if len(ticks) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO tickdata (instrument_token, timestamp, price) VALUES (?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, tick := range ticks {
+		if _, err := stmt.ExecContext(ctx, tick.Token, tick.TimeStamp, tick.LastPrice); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Close closes the underlying database connection.
+func (s *ClickHouseSink) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,62 @@
+package tickstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// KafkaSink publishes each flushed batch as a run of JSON-encoded Kafka
+// messages, keyed by instrument token so all ticks for a token land on the
+// same partition and stay ordered.
+type KafkaSink struct {
+	writer   *kafka.Writer
+	dumpSize int
+}
+
+// NewKafkaSink returns a Sink that publishes to topic on the given Kafka
+// brokers, flushing batches of up to dumpSize ticks per write.
+func NewKafkaSink(brokers []string, topic string, dumpSize int) *KafkaSink {
+	return &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Topic:    topic,
+			Balancer: &kafka.Hash{},
+		},
+		dumpSize: dumpSize,
+	}
+}
+
+// MakeBatch returns a fresh batch sized to dumpSize.
+func (s *KafkaSink) MakeBatch() Batch {
+	return &sliceBatch{dumpSize: s.dumpSize}
+}
+
+// Flush publishes each tick in batch as a Kafka message.
+func (s *KafkaSink) Flush(ctx context.Context, batch Batch) error {
+	ticks := batch.Ticks()
+	if len(ticks) == 0 {
+		return nil
+	}
+
+	msgs := make([]kafka.Message, 0, len(ticks))
+	for _, tick := range ticks {
+		value, err := json.Marshal(tick)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, kafka.Message{
+			Key:   []byte(fmt.Sprintf("%d", tick.Token)),
+			Value: value,
+		})
+	}
+
+	return s.writer.WriteMessages(ctx, msgs...)
+}
+
+// Close closes the underlying Kafka writer.
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
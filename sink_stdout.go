@@ -0,0 +1,43 @@
+package tickstore
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// StdoutJSONSink writes each tick as a line of JSON to an io.Writer. It's
+// handy for local development and for inspecting a stream without standing
+// up ClickHouse.
+type StdoutJSONSink struct {
+	enc *json.Encoder
+}
+
+// NewStdoutJSONSink returns a Sink that writes newline-delimited JSON to w.
+// Passing a nil w writes to os.Stdout.
+func NewStdoutJSONSink(w io.Writer) *StdoutJSONSink {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &StdoutJSONSink{enc: json.NewEncoder(w)}
+}
+
+// MakeBatch returns a fresh batch; StdoutJSONSink flushes every tick as it
+// arrives, so the batch never holds more than one.
+func (s *StdoutJSONSink) MakeBatch() Batch {
+	return &sliceBatch{dumpSize: 1}
+}
+
+// Flush writes each tick in batch as a JSON line.
+func (s *StdoutJSONSink) Flush(ctx context.Context, batch Batch) error {
+	for _, tick := range batch.Ticks() {
+		if err := s.enc.Encode(tick); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close is a no-op; StdoutJSONSink does not own its writer.
+func (s *StdoutJSONSink) Close() error { return nil }
@@ -0,0 +1,26 @@
+package tickstore
+
+import "testing"
+
+func TestSliceBatch(t *testing.T) {
+	b := &sliceBatch{dumpSize: 2}
+
+	if b.Len() != 0 || b.ShouldFlush() {
+		t.Fatalf("new batch should be empty and not ready to flush, got len=%d shouldFlush=%v", b.Len(), b.ShouldFlush())
+	}
+
+	b.Append(tickData{Token: 1, LastPrice: 100})
+	if b.Len() != 1 || b.ShouldFlush() {
+		t.Fatalf("batch with 1/2 ticks should not be ready to flush, got len=%d shouldFlush=%v", b.Len(), b.ShouldFlush())
+	}
+
+	b.Append(tickData{Token: 1, LastPrice: 101})
+	if b.Len() != 2 || !b.ShouldFlush() {
+		t.Fatalf("batch at dumpSize should be ready to flush, got len=%d shouldFlush=%v", b.Len(), b.ShouldFlush())
+	}
+
+	ticks := b.Ticks()
+	if len(ticks) != 2 || ticks[0].LastPrice != 100 || ticks[1].LastPrice != 101 {
+		t.Fatalf("Ticks() should return appended ticks in order, got %+v", ticks)
+	}
+}
@@ -1,10 +1,8 @@
 package tickstore
 
 import (
-	"database/sql"
-	"fmt"
-	"log"
-	"sync"
+	"context"
+	"sync/atomic"
 	"time"
 
 	kitemodels "github.com/zerodha/gokiteconnect/v4/models"
@@ -16,145 +14,190 @@ type tickData struct {
 	Token     uint32
 	TimeStamp time.Time
 	LastPrice float64
+	Volume    float64
 }
 
-var (
-	dbConnect   *sql.DB
-	ticker      *kiteticker.Ticker
-	wg          sync.WaitGroup
-	isBulkReady sync.Mutex
-	dumpSize    int
-	tokens      []uint32
-	pipeline    chan tickData
-)
-
 // Triggered when any error is raised
-func onError(err error) {
-	fmt.Println("Error: ", err)
+func (c *Client) onError(err error) {
+	c.Logger.Error("ticker error", "err", err)
 }
 
 // Triggered when websocket connection is closed
-func onClose(code int, reason string) {
-	fmt.Println("Close: ", code, reason)
+func (c *Client) onClose(code int, reason string) {
+	c.Logger.Warn("ticker closed", "code", code, "reason", reason)
 }
 
-// Triggered when connection is established and ready to send and accept data
-func onConnect() {
-	fmt.Println("Connected")
-	err := ticker.Subscribe(tokens)
-	if err != nil {
-		fmt.Println("err: ", err)
-	}
-	// Set subscription mode for given list of tokens
-	err = ticker.SetMode(kiteticker.ModeFull, tokens)
-	if err != nil {
-		fmt.Println("err: ", err)
+// Triggered when connection is established and ready to send and accept
+// data. Runs on the initial connect and every reconnect, so it always
+// (re)subscribes to the current token set rather than a stale snapshot.
+// It also resets the watchdog's staleness clock, so a connection that
+// never delivers a tick can still be detected as stalled, and a
+// reconnect that took a while (e.g. backoff) doesn't get force-closed
+// again before it's had a full PingWait to prove itself.
+func (c *Client) onConnect() {
+	c.Logger.Info("ticker connected")
+	c.lastTick.Store(time.Now())
+	if err := c.subscribeTokens(c.currentTokens()); err != nil {
+		c.Logger.Error("subscribe failed", "err", err)
 	}
 }
 
-// Triggered when tick is received
-func onTick(tick kitemodels.Tick) {
-	wg.Add(1)
-	//go routine that adds tick to channel
-	go passChannel(tick, pipeline, &wg)
-	// non-blocking the onTick callback
-	wg.Wait()
-}
-
-// Triggered when reconnection is attempted which is enabled by default
-func onReconnect(attempt int, delay time.Duration) {
-	fmt.Printf("Reconnect attempt %d in %fs\n", attempt, delay.Seconds())
-}
+// Triggered when tick is received. The send is a bounded, non-blocking
+// attempt: if the pipeline is full the tick is dropped and counted rather
+// than blocking the ticker's callback goroutine.
+func (c *Client) onTick(tick kitemodels.Tick) {
+	t := tickData{tick.InstrumentToken, tick.Timestamp.Time, tick.LastPrice, float64(tick.LastTradedQuantity)}
+	c.lastTick.Store(time.Now())
 
-// Triggered when maximum number of reconnect attempt is made and the program is terminated
-func onNoReconnect(attempt int) {
-	fmt.Printf("Maximum no of reconnect attempt reached: %d", attempt)
-}
+	if c.metrics != nil {
+		c.metrics.recordTick(t.Token)
+	}
 
-// Insert tick data to channel
-func passChannel(tick kitemodels.Tick, pipeline chan tickData, wg *sync.WaitGroup) {
-	// Send {token, timestamp, lastprice} struct to channel
-	pipeline <- tickData{tick.InstrumentToken, tick.Timestamp.Time, tick.LastPrice}
-	wg.Done()
-	isBulkReady.Lock()
-	// Send for bulk insertion only if channel msg length is greater than defined dumpSize
-	if len(pipeline) >= dumpSize {
-		createBulkDump()
+	if c.Aggregator != nil {
+		c.Aggregator.Add(t)
 	}
-	isBulkReady.Unlock()
-}
 
-// Group all available channel messages and bulk insert to clickhouse
-// At periodic interval depending on users input channel buffer size
-func createBulkDump() {
-	s := make([]tickData, 0)
-	for i := range pipeline {
-		// create array of ticks to do bulk insert
-		s = append(s, i)
-		if len(s) > dumpSize {
-			// Send message array for the bulk dump
-			insertDB(s)
-			// Remove all the element from the array that is dumped to DB
-			s = nil
+	select {
+	case c.pipeline <- t:
+	default:
+		atomic.AddUint64(&c.droppedTicks, 1)
+		if c.metrics != nil {
+			c.metrics.recordDrop()
 		}
 	}
 }
 
-// Insert tick data to clickhouse periodically
-func insertDB(tickArray []tickData) {
-	tx, err := dbConnect.Begin()
-	if err != nil {
-		log.Fatal(err)
+// Triggered when reconnection is attempted which is enabled by default
+func (c *Client) onReconnect(attempt int, delay time.Duration) {
+	c.Logger.Warn("ticker reconnecting", "attempt", attempt, "delay", delay)
+	if c.metrics != nil {
+		c.metrics.recordReconnect()
 	}
+}
 
-	sqlstmt := "INSERT INTO tickdata (instrument_token, timestamp, price) VALUES (?, ?, ?)"
+// Triggered when maximum number of reconnect attempt is made; Serve then
+// returns and StartTicker drains the pipeline, closes the sink and returns
+func (c *Client) onNoReconnect(attempt int) {
+	c.Logger.Error("max reconnect attempts reached", "attempt", attempt)
+}
 
-	stmt, err := tx.Prepare(sqlstmt)
-	if err != nil {
-		log.Fatal(err)
+// createBulkDump drains the pipeline, handing each tick to the sink's
+// current Batch. A batch is flushed whenever it says it's ready, or
+// whenever FlushInterval elapses with ticks still buffered. Once ctx is
+// cancelled it stops selecting on it and switches to draining c.pipeline
+// to completion instead of returning outright: Stop cancels ctx well
+// before the ticker connection actually tears down and c.pipeline is
+// closed, so ticks keep arriving through that window and would otherwise
+// be silently lost. Once the pipeline reports closed, it flushes whatever
+// is left with a fresh context - the ctx that's shutting things down has
+// usually already expired by the time the drain runs - and returns.
+func (c *Client) createBulkDump(ctx context.Context) error {
+	flush := time.NewTicker(c.FlushInterval)
+	defer flush.Stop()
+
+	batch := c.sink.MakeBatch()
+
+	flushBatch := func(flushCtx context.Context) {
+		if batch.Len() == 0 {
+			return
+		}
+		c.flushWithRetry(flushCtx, batch)
+		batch = c.sink.MakeBatch()
 	}
 
-	// Bulk write
-	for _, tick := range tickArray {
-		if _, err := stmt.Exec(
-			tick.Token,
-			tick.TimeStamp,
-			tick.LastPrice,
-		); err != nil {
-			log.Fatal(err)
+	drain := func() error {
+		for tick := range c.pipeline {
+			batch.Append(tick)
+			if batch.ShouldFlush() {
+				flushBatch(context.Background())
+			}
 		}
+		flushBatch(context.Background())
+		return nil
 	}
 
-	if err := tx.Commit(); err != nil {
-		log.Fatal(err)
+	for {
+		select {
+		case tick, ok := <-c.pipeline:
+			if !ok {
+				flushBatch(context.Background())
+				return nil
+			}
+			batch.Append(tick)
+			if batch.ShouldFlush() {
+				flushBatch(ctx)
+			}
+
+		case <-flush.C:
+			flushBatch(ctx)
+
+		case <-ctx.Done():
+			return drain()
+		}
 	}
 }
 
-// Start ticker stream
-func (c *Client) StartTicker() {
-
-	dbConnect = c.dbClient
-
-	dumpSize = c.dumpSize
-
-	tokens = c.tokenList
-
+// StartTicker connects to the Kite ticker websocket, subscribes to
+// c.tokenList and streams ticks to c.sink until ctx is cancelled, Stop is
+// called, or the connection is closed for good. It blocks until the stream
+// has fully drained and the sink is closed, then returns the first fatal
+// error encountered, if any.
+func (c *Client) StartTicker(ctx context.Context) error {
 	// Channel to store all upcoming streams of ticks
-	pipeline = make(chan tickData, dumpSize)
+	c.setPipeline(make(chan tickData, c.dumpSize))
+	c.stopped = make(chan struct{})
 
-	// Create new Kite ticker instance
-	ticker = kiteticker.New(c.apiKey, c.accessToken)
+	c.tokensMu.Lock()
+	c.subscribed = append([]uint32(nil), c.tokenList...)
+	c.tokensMu.Unlock()
 
-	ticker.SetReconnectMaxRetries(5)
+	// Create new Kite ticker instance
+	c.ticker = kiteticker.New(c.apiKey, c.accessToken)
+	c.ticker.SetReconnectMaxRetries(5)
 
 	// Assign callbacks
-	ticker.OnError(onError)
-	ticker.OnClose(onClose)
-	ticker.OnConnect(onConnect)
-	ticker.OnReconnect(onReconnect)
-	ticker.OnNoReconnect(onNoReconnect)
-	ticker.OnTick(onTick)
-
-	// Start the connection
-	ticker.Serve()
+	c.ticker.OnError(c.onError)
+	c.ticker.OnClose(c.onClose)
+	c.ticker.OnConnect(c.onConnect)
+	c.ticker.OnReconnect(c.onReconnect)
+	c.ticker.OnNoReconnect(c.onNoReconnect)
+	c.ticker.OnTick(c.onTick)
+
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.createBulkDump(ctx)
+	}()
+	go c.watchdog(ctx)
+
+	// When the context is cancelled (by the caller or by Stop), tear the
+	// connection down so Serve below returns and the drain can proceed.
+	go func() {
+		<-ctx.Done()
+		c.ticker.Close()
+	}()
+
+	// Start the connection; blocks until the ticker gives up on the
+	// connection for good.
+	c.ticker.Serve()
+
+	close(c.pipeline)
+	err := <-errCh
+
+	// No more ticks will arrive past this point, so it's safe to flush and
+	// close the aggregator's Candles channel alongside the sink.
+	if c.Aggregator != nil {
+		c.Aggregator.Close()
+	}
+
+	if sinkErr := c.sink.Close(); err == nil {
+		err = sinkErr
+	}
+
+	c.stopErr = err
+	close(c.stopped)
+	return err
 }